@@ -24,6 +24,8 @@ import (
 	"github.com/coreos/rkt/rkt/config"
 	"github.com/coreos/rkt/store/imagestore"
 	"github.com/hashicorp/errwrap"
+
+	"github.com/coreos/ioprogress"
 )
 
 // httpOps is a kind of facade around a downloader and a
@@ -96,8 +98,13 @@ func (o *httpOps) DownloadImageWithETag(u *url.URL, etag string) (readSeekCloser
 	session := o.getSession(u, aciFile.File, "ACI", etag)
 	dl := o.getDownloader(session)
 	if err := dl.Download(u, aciFile.File); err != nil {
+		o.maybeKeepPartial(aciFile)
+		aciFile = nil
 		return nil, nil, errwrap.Wrap(errors.New("error downloading ACI"), err)
 	}
+	if err := os.Remove(session.ETagFilePath); err != nil && !os.IsNotExist(err) {
+		return nil, nil, errwrap.Wrap(errors.New("error removing the ETag file of a finished download"), err)
+	}
 	if session.Cd.UseCached {
 		return nil, session.Cd, nil
 	}
@@ -106,6 +113,22 @@ func (o *httpOps) DownloadImageWithETag(u *url.URL, etag string) (readSeekCloser
 	return retAciFile, session.Cd, nil
 }
 
+// maybeKeepPartial closes the temporary file of an interrupted
+// download. If some data was already downloaded, the file is kept on
+// disk, so the next fetch of the same URL can resume from where this
+// one stopped. Otherwise the file is removed.
+func (o *httpOps) maybeKeepPartial(f *removeOnClose) {
+	fi, err := f.File.Stat()
+	if err != nil || fi.Size() < 1 {
+		maybeClose(f)
+		return
+	}
+	log.Printf("keeping %s of partially downloaded data for resuming the download later", ioprogress.ByteUnitStr(fi.Size()))
+	if err := f.Keep(); err != nil {
+		log.PrintE("failed to close the partially downloaded file", err)
+	}
+}
+
 // AscRemoteFetcher provides a remoteAscFetcher for asc.
 func (o *httpOps) AscRemoteFetcher() *remoteAscFetcher {
 	ensureLogger(o.Debug)
@@ -121,6 +144,9 @@ func (o *httpOps) AscRemoteFetcher() *remoteAscFetcher {
 		if err != nil {
 			return err
 		}
+		if err := os.Remove(session.ETagFilePath); err != nil && !os.IsNotExist(err) {
+			return errwrap.Wrap(errors.New("error removing the ETag file of a finished download"), err)
+		}
 		if session.Cd.UseCached {
 			return fmt.Errorf("unexpected cache reuse request for signature %q", u.String())
 		}
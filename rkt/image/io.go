@@ -103,6 +103,13 @@ func (f *removeOnClose) Close() error {
 	return nil
 }
 
+// Keep closes the file, but leaves it on disk. It is used for keeping
+// partially downloaded data around, so the download can be resumed
+// later.
+func (f *removeOnClose) Keep() error {
+	return f.File.Close()
+}
+
 // getTmpROC returns a removeOnClose instance wrapping a temporary
 // file provided by the passed store. The actual file name is based on
 // a hash of the passed path.
@@ -16,6 +16,7 @@ package image
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/coreos/rkt/rkt/config"
 	"github.com/coreos/rkt/version"
+	"github.com/hashicorp/errwrap"
 )
 
 // statusAcceptedError is an error returned when resumableSession
@@ -100,6 +102,9 @@ func (s *resumableSession) Request(u *url.URL) (*http.Request, error) {
 func (s *resumableSession) HandleStatus(res *http.Response) (bool, error) {
 	switch res.StatusCode {
 	case http.StatusOK, http.StatusPartialContent:
+		if err := s.handleContent(res); err != nil {
+			return false, err
+		}
 		fallthrough
 	case http.StatusNotModified:
 		s.Cd = &cacheData{
@@ -124,6 +129,31 @@ func (s *resumableSession) BodyReader(res *http.Response) (io.Reader, error) {
 	return reader, nil
 }
 
+// handleContent prepares the file for receiving the response
+// body. If the server ignored our range request and sent the whole
+// resource, the partially downloaded data is dropped. The ETag of the
+// resource is saved, so the download can be resumed if it gets
+// interrupted.
+func (s *resumableSession) handleContent(res *http.Response) error {
+	if res.StatusCode == http.StatusOK && s.amountAlreadyHere > 0 {
+		log.Printf("server sent the whole resource, discarding the partial download")
+		if err := s.reset(); err != nil {
+			return err
+		}
+	}
+	if res.StatusCode == http.StatusPartialContent {
+		log.Printf("resuming the download of %s from byte %d", s.Label, s.amountAlreadyHere)
+	}
+	etag := res.Header.Get("ETag")
+	if etag == "" || s.ETagFilePath == "" {
+		return nil
+	}
+	if err := ioutil.WriteFile(s.ETagFilePath, []byte(etag), 0644); err != nil {
+		return errwrap.Wrap(errors.New("failed to save the ETag for resuming the download"), err)
+	}
+	return nil
+}
+
 type rangeStatus int
 
 const (
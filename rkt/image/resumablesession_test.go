@@ -15,6 +15,9 @@
 package image
 
 import (
+	"io/ioutil"
+	"net/http"
+	"os"
 	"testing"
 )
 
@@ -45,3 +48,71 @@ func TestGetMaxAge(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleContent(t *testing.T) {
+	ensureLogger(false)
+	dir, err := ioutil.TempDir("", "rkt-resumable-test")
+	if err != nil {
+		t.Fatalf("failed to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		status      int
+		etag        string
+		alreadyHere int64
+		expSize     int64
+	}{
+		{
+			// server ignored the range request, partial data is dropped
+			status:      http.StatusOK,
+			etag:        `"abc"`,
+			alreadyHere: 4,
+			expSize:     0,
+		},
+		{
+			// server honored the range request, partial data is kept
+			status:      http.StatusPartialContent,
+			etag:        `"def"`,
+			alreadyHere: 4,
+			expSize:     4,
+		},
+	}
+	for i, tt := range tests {
+		f, err := ioutil.TempFile(dir, "partial")
+		if err != nil {
+			t.Fatalf("failed to create a temporary file: %v", err)
+		}
+		if _, err := f.Write([]byte("data")); err != nil {
+			t.Fatalf("failed to write to a temporary file: %v", err)
+		}
+		s := &resumableSession{
+			File:               f,
+			ETagFilePath:       f.Name() + ".etag",
+			amountAlreadyHere:  tt.alreadyHere,
+			byteRangeSupported: true,
+		}
+		res := &http.Response{
+			StatusCode: tt.status,
+			Header:     http.Header{"Etag": []string{tt.etag}},
+		}
+		if err := s.handleContent(res); err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			t.Fatalf("#%d: failed to stat the file: %v", i, err)
+		}
+		if fi.Size() != tt.expSize {
+			t.Errorf("#%d: expected file size %d, got %d", i, tt.expSize, fi.Size())
+		}
+		etag, err := ioutil.ReadFile(s.ETagFilePath)
+		if err != nil {
+			t.Fatalf("#%d: failed to read the ETag file: %v", i, err)
+		}
+		if string(etag) != tt.etag {
+			t.Errorf("#%d: expected saved ETag %q, got %q", i, tt.etag, string(etag))
+		}
+		f.Close()
+	}
+}